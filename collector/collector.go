@@ -0,0 +1,62 @@
+// Package collector abstracts per-process and per-disk IO sampling behind a
+// single interface so the TUI never has to know whether it's reading PDH
+// counters on Windows or /proc on Linux.
+package collector
+
+// ProcIO is one process's cumulative IO counters as of the last sample.
+// Rates are derived by the caller by diffing two snapshots.
+type ProcIO struct {
+	PID   uint32
+	Name  string
+	User  string // owning user, best-effort; empty if it could not be resolved
+	Read  uint64
+	Write uint64
+}
+
+// CPUStats is per-core utilization, 0-100.
+type CPUStats struct {
+	PerCore []float64
+}
+
+// MemStats is system memory in bytes.
+type MemStats struct {
+	Total     uint64
+	Available uint64
+}
+
+// NetStats is aggregate network throughput across all interfaces.
+type NetStats struct {
+	RxBytesPerSec float64
+	TxBytesPerSec float64
+}
+
+// DiskIO is per-physical-disk throughput, keyed by device name.
+type DiskIO struct {
+	Name             string
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+}
+
+// Collector samples process IO and disk pressure for the host platform.
+// Implementations are selected at build time via //go:build tags; see
+// collector_windows.go and collector_unix.go.
+type Collector interface {
+	// Processes returns cumulative read/write byte counters for every
+	// process currently visible to the collector.
+	Processes() ([]ProcIO, error)
+	// DiskPressure returns a single scalar load metric for physical
+	// disks, comparable across platforms but not in absolute units.
+	DiskPressure() (float64, error)
+	// CPU returns current per-core utilization.
+	CPU() (CPUStats, error)
+	// Memory returns current system memory usage.
+	Memory() (MemStats, error)
+	// Network returns aggregate throughput since the last call.
+	Network() (NetStats, error)
+	// Disks returns per-physical-disk throughput since the last call.
+	Disks() ([]DiskIO, error)
+	// Health returns SMART/NVMe health attributes for each physical
+	// drive, throttled internally to at most once per healthPollInterval.
+	Health() ([]DiskHealth, error)
+	Close() error
+}
@@ -0,0 +1,444 @@
+//go:build windows
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// ----------------------- WIN32 / PDH -----------------------
+var (
+	k32 = windows.NewLazySystemDLL("kernel32.dll")
+	pdh = windows.NewLazySystemDLL("pdh.dll")
+
+	pGetIOCounters        = k32.NewProc("GetProcessIoCounters")
+	pGlobalMemoryStatusEx = k32.NewProc("GlobalMemoryStatusEx")
+
+	pPdhOpenQuery     = pdh.NewProc("PdhOpenQueryW")
+	pPdhAddCounter    = pdh.NewProc("PdhAddEnglishCounterW")
+	pPdhCollect       = pdh.NewProc("PdhCollectQueryData")
+	pPdhGetValue      = pdh.NewProc("PdhGetFormattedCounterValue")
+	pPdhGetValueArray = pdh.NewProc("PdhGetFormattedCounterArrayW")
+	pPdhCloseQuery    = pdh.NewProc("PdhCloseQuery")
+)
+
+const (
+	pdhFmtDouble = 0x00000200
+	pdhMoreData  = 0x800007D2
+)
+
+// pdhCounterArrayItem mirrors PDH_FMT_COUNTERVALUE_ITEM_W: a wide-string
+// instance name pointer followed by its PDH_FMT_COUNTERVALUE. Go lays out
+// the embedded struct with the same padding the Win32 header uses, so no
+// explicit alignment bytes are needed here.
+type pdhCounterArrayItem struct {
+	name  *uint16
+	value struct {
+		CStatus     uint32
+		DoubleValue float64
+	}
+}
+
+// readCounterArray collects and formats a wildcarded PDH counter (one
+// whose instance is "*"), returning one value per matched instance name.
+// It does the standard PDH two-pass dance: the first call reports how
+// large a buffer PDH needs, the second fills it.
+func readCounterArray(counter uintptr) (map[string]float64, error) {
+	var bufSize, itemCount uint32
+	r, _, _ := pPdhGetValueArray.Call(counter, pdhFmtDouble,
+		uintptr(unsafe.Pointer(&bufSize)), uintptr(unsafe.Pointer(&itemCount)), 0)
+	if r != 0 && r != pdhMoreData {
+		return nil, fmt.Errorf("PdhGetFormattedCounterArrayW (size): status 0x%x", r)
+	}
+	if itemCount == 0 {
+		return map[string]float64{}, nil
+	}
+
+	// bufSize covers the item array plus the instance-name strings PDH
+	// stores after it, so allocate bytes rather than itemCount items.
+	buf := make([]byte, bufSize)
+	r, _, _ = pPdhGetValueArray.Call(counter, pdhFmtDouble,
+		uintptr(unsafe.Pointer(&bufSize)), uintptr(unsafe.Pointer(&itemCount)),
+		uintptr(unsafe.Pointer(&buf[0])))
+	if r != 0 {
+		return nil, fmt.Errorf("PdhGetFormattedCounterArrayW: status 0x%x", r)
+	}
+	items := unsafe.Slice((*pdhCounterArrayItem)(unsafe.Pointer(&buf[0])), itemCount)
+
+	out := make(map[string]float64, itemCount)
+	for _, item := range items[:itemCount] {
+		out[windows.UTF16PtrToString(item.name)] = item.value.DoubleValue
+	}
+	return out, nil
+}
+
+// addCounter adds a counter path to the given PDH query and returns its
+// counter handle. path may use a "*" instance wildcard, in which case
+// readCounterArray (rather than PdhGetFormattedCounterValue) must be used
+// to read it back.
+func addCounter(query uintptr, path string) uintptr {
+	p, _ := windows.UTF16PtrFromString(path)
+	var c uintptr
+	pPdhAddCounter.Call(query, uintptr(unsafe.Pointer(p)), 0, uintptr(unsafe.Pointer(&c)))
+	return c
+}
+
+// ----------------------- PROCESS HANDLE CACHE -----------------------
+type handleCache struct {
+	handles map[uint32]windows.Handle
+	users   map[uint32]string // resolved lazily; a process's owner never changes
+}
+
+func newHandleCache() *handleCache {
+	return &handleCache{
+		handles: make(map[uint32]windows.Handle),
+		users:   make(map[uint32]string),
+	}
+}
+
+func (hc *handleCache) get(pid uint32) (windows.Handle, error) {
+	if h, exists := hc.handles[pid]; exists {
+		return h, nil
+	}
+	// Try to open the handle
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return 0, err
+	}
+	hc.handles[pid] = h
+	return h, nil
+}
+
+// username returns the DOMAIN\Account that owns pid, resolved once per
+// process via its token and cached thereafter. Left blank if the token
+// can't be opened or the SID can't be resolved (e.g. a protected system
+// process without admin rights).
+func (hc *handleCache) username(pid uint32, h windows.Handle) string {
+	if u, ok := hc.users[pid]; ok {
+		return u
+	}
+	u := lookupProcessUser(h)
+	hc.users[pid] = u
+	return u
+}
+
+func (hc *handleCache) closeAll() {
+	for pid, h := range hc.handles {
+		windows.CloseHandle(h)
+		delete(hc.handles, pid)
+	}
+}
+
+func (hc *handleCache) cleanup(validPIDs map[uint32]bool) {
+	for pid, h := range hc.handles {
+		if !validPIDs[pid] {
+			windows.CloseHandle(h)
+			delete(hc.handles, pid)
+			delete(hc.users, pid)
+		}
+	}
+}
+
+// lookupProcessUser resolves the account that owns a process from its
+// token, via OpenProcessToken + GetTokenInformation(TokenUser) +
+// LookupAccountSid.
+func lookupProcessUser(h windows.Handle) string {
+	var token windows.Token
+	if err := windows.OpenProcessToken(h, windows.TOKEN_QUERY, &token); err != nil {
+		return ""
+	}
+	defer token.Close()
+
+	tu, err := token.GetTokenUser()
+	if err != nil {
+		return ""
+	}
+
+	account, domain, _, err := tu.User.Sid.LookupAccount("")
+	if err != nil {
+		return ""
+	}
+	if domain != "" {
+		return domain + `\` + account
+	}
+	return account
+}
+
+// ----------------------- DISK QUEUE -----------------------
+type diskQueue struct {
+	query   uintptr
+	counter uintptr
+}
+
+func newDiskQueue() *diskQueue {
+	var q uintptr
+	pPdhOpenQuery.Call(0, 0, uintptr(unsafe.Pointer(&q)))
+	c := addCounter(q, `\PhysicalDisk(_Total)\Avg. Disk Queue Length`)
+	pPdhCollect.Call(q)
+	return &diskQueue{query: q, counter: c}
+}
+
+func (d *diskQueue) read() float64 {
+	pPdhCollect.Call(d.query)
+	var val struct {
+		CStatus     uint32
+		DoubleValue float64
+	}
+	pPdhGetValue.Call(d.counter, 0x00000200, 0, uintptr(unsafe.Pointer(&val)))
+	return val.DoubleValue
+}
+
+func (d *diskQueue) close() {
+	pPdhCloseQuery.Call(d.query)
+}
+
+// ----------------------- DASHBOARD COUNTERS -----------------------
+// dashboardResampleDebounce keeps to at most one PdhCollectQueryData per
+// tick even though CPU/Memory/Network/Disks may each be polled by a
+// separate panel within that tick. At intervals under 250ms some ticks
+// reuse the previous collection.
+const dashboardResampleDebounce = 250 * time.Millisecond
+
+type dashboardQuery struct {
+	query            uintptr
+	cpuCounter       uintptr
+	memCounter       uintptr
+	netRecvCounter   uintptr
+	netSentCounter   uintptr
+	diskReadCounter  uintptr
+	diskWriteCounter uintptr
+	lastCollect      time.Time
+}
+
+func newDashboardQuery() *dashboardQuery {
+	var q uintptr
+	pPdhOpenQuery.Call(0, 0, uintptr(unsafe.Pointer(&q)))
+	d := &dashboardQuery{
+		query:            q,
+		cpuCounter:       addCounter(q, `\Processor Information(*)\% Processor Time`),
+		memCounter:       addCounter(q, `\Memory\Available Bytes`),
+		netRecvCounter:   addCounter(q, `\Network Interface(*)\Bytes Received/sec`),
+		netSentCounter:   addCounter(q, `\Network Interface(*)\Bytes Sent/sec`),
+		diskReadCounter:  addCounter(q, `\PhysicalDisk(*)\Disk Read Bytes/sec`),
+		diskWriteCounter: addCounter(q, `\PhysicalDisk(*)\Disk Write Bytes/sec`),
+	}
+	pPdhCollect.Call(q)
+	return d
+}
+
+// collect refreshes all dashboard counters together, debounced so
+// multiple panels reading within the same tick see a consistent sample.
+func (d *dashboardQuery) collect() {
+	if time.Since(d.lastCollect) < dashboardResampleDebounce {
+		return
+	}
+	pPdhCollect.Call(d.query)
+	d.lastCollect = time.Now()
+}
+
+func (d *dashboardQuery) close() {
+	pPdhCloseQuery.Call(d.query)
+}
+
+// ----------------------- WINDOWS COLLECTOR -----------------------
+type windowsCollector struct {
+	cache     *handleCache
+	disk      *diskQueue
+	dashboard *dashboardQuery
+
+	healthCache    []DiskHealth
+	lastHealthPoll time.Time
+}
+
+// New returns the Windows PDH/Toolhelp32-backed Collector.
+func New() (Collector, error) {
+	return &windowsCollector{
+		cache:     newHandleCache(),
+		disk:      newDiskQueue(),
+		dashboard: newDashboardQuery(),
+	}, nil
+}
+
+func (w *windowsCollector) Processes() ([]ProcIO, error) {
+	var out []ProcIO
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(snap)
+
+	var pe windows.ProcessEntry32
+	pe.Size = uint32(unsafe.Sizeof(pe))
+	if windows.Process32First(snap, &pe) != nil {
+		return out, nil
+	}
+
+	validPIDs := make(map[uint32]bool)
+	for {
+		validPIDs[pe.ProcessID] = true
+
+		h, err := w.cache.get(pe.ProcessID)
+		if err == nil {
+			var io windows.IO_COUNTERS
+			if r, _, _ := pGetIOCounters.Call(uintptr(h), uintptr(unsafe.Pointer(&io))); r != 0 {
+				out = append(out, ProcIO{
+					PID:   pe.ProcessID,
+					Name:  windows.UTF16ToString(pe.ExeFile[:]),
+					User:  w.cache.username(pe.ProcessID, h),
+					Read:  io.ReadTransferCount,
+					Write: io.WriteTransferCount,
+				})
+			}
+		}
+
+		if windows.Process32Next(snap, &pe) != nil {
+			break
+		}
+	}
+
+	// Clean up handles for PIDs that no longer exist
+	w.cache.cleanup(validPIDs)
+
+	return out, nil
+}
+
+func (w *windowsCollector) DiskPressure() (float64, error) {
+	return w.disk.read(), nil
+}
+
+func (w *windowsCollector) CPU() (CPUStats, error) {
+	w.dashboard.collect()
+	cores, err := readCounterArray(w.dashboard.cpuCounter)
+	if err != nil {
+		return CPUStats{}, err
+	}
+
+	// Sort instance names so each core keeps the same index on every call;
+	// "_Total" and per-node "N,_Total" instances are aggregates, not cores.
+	names := make([]string, 0, len(cores))
+	for name := range cores {
+		if strings.HasSuffix(name, "_Total") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var stats CPUStats
+	for _, name := range names {
+		stats.PerCore = append(stats.PerCore, cores[name])
+	}
+	return stats, nil
+}
+
+// memoryStatusEx mirrors MEMORYSTATUSEX. x/sys/windows doesn't wrap
+// GlobalMemoryStatusEx, so we call it directly like every other Win32 API
+// in this file.
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+func (w *windowsCollector) Memory() (MemStats, error) {
+	w.dashboard.collect()
+	var val struct {
+		CStatus     uint32
+		DoubleValue float64
+	}
+	pPdhGetValue.Call(w.dashboard.memCounter, pdhFmtDouble, 0, uintptr(unsafe.Pointer(&val)))
+
+	var mem memoryStatusEx
+	mem.Length = uint32(unsafe.Sizeof(mem))
+	pGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&mem)))
+
+	return MemStats{Total: mem.TotalPhys, Available: uint64(val.DoubleValue)}, nil
+}
+
+func (w *windowsCollector) Network() (NetStats, error) {
+	w.dashboard.collect()
+	recv, err := readCounterArray(w.dashboard.netRecvCounter)
+	if err != nil {
+		return NetStats{}, err
+	}
+	sent, err := readCounterArray(w.dashboard.netSentCounter)
+	if err != nil {
+		return NetStats{}, err
+	}
+
+	var stats NetStats
+	for name, v := range recv {
+		if strings.Contains(name, "isatap") || strings.Contains(name, "Loopback") {
+			continue
+		}
+		stats.RxBytesPerSec += v
+	}
+	for name, v := range sent {
+		if strings.Contains(name, "isatap") || strings.Contains(name, "Loopback") {
+			continue
+		}
+		stats.TxBytesPerSec += v
+	}
+	return stats, nil
+}
+
+func (w *windowsCollector) Disks() ([]DiskIO, error) {
+	w.dashboard.collect()
+	reads, err := readCounterArray(w.dashboard.diskReadCounter)
+	if err != nil {
+		return nil, err
+	}
+	writes, err := readCounterArray(w.dashboard.diskWriteCounter)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []DiskIO
+	for name, r := range reads {
+		if name == "_Total" {
+			continue
+		}
+		out = append(out, DiskIO{
+			Name:             name,
+			ReadBytesPerSec:  r,
+			WriteBytesPerSec: writes[name],
+		})
+	}
+	return out, nil
+}
+
+// Health returns SMART/NVMe attributes per physical drive, probing the
+// hardware (via queryDriveHealth) at most once per healthPollInterval
+// regardless of how often it's called.
+func (w *windowsCollector) Health() ([]DiskHealth, error) {
+	if w.healthCache != nil && time.Since(w.lastHealthPoll) < healthPollInterval {
+		return w.healthCache, nil
+	}
+
+	health, err := queryDriveHealth()
+	if err != nil {
+		return nil, err
+	}
+	w.healthCache = health
+	w.lastHealthPoll = time.Now()
+	return health, nil
+}
+
+func (w *windowsCollector) Close() error {
+	w.disk.close()
+	w.dashboard.close()
+	w.cache.closeAll()
+	return nil
+}
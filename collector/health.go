@@ -0,0 +1,75 @@
+package collector
+
+import "time"
+
+// DiskHealth is a point-in-time SMART/NVMe health reading for one
+// physical drive. It's sampled independently of the throughput counters
+// in Disks(): the underlying ATA/NVMe passthrough queries are slower and
+// change slowly, so collectors throttle them to healthPollInterval
+// regardless of how often Health is called.
+type DiskHealth struct {
+	Device string
+	NVMe   bool
+	// Available is false when the drive could not be queried at all,
+	// e.g. the process lacks admin rights for the passthrough IOCTL. The
+	// other fields are zero in that case rather than omitted, so callers
+	// can still render a row for the drive.
+	Available bool
+
+	TemperatureC     int
+	ReallocatedCount uint64
+	PendingCount     uint64
+
+	// NVMe-only.
+	PercentageUsed uint8
+	MediaErrors    uint64
+}
+
+// healthPollInterval is the minimum time between SMART/NVMe polls,
+// independent of the TUI tick.
+const healthPollInterval = 30 * time.Second
+
+// ATA SMART command constants (ACS-3 SMART feature set), shared by the
+// Windows ATA_PASS_THROUGH and Linux SG_IO/ATA_16 implementations.
+const (
+	ataCmdSMART             = 0xB0 // SMART command class
+	ataFeatureSMARTReadData = 0xD0 // SMART READ DATA subcommand
+
+	smartAttrReallocatedSectorCount = 5
+	smartAttrTemperature            = 194
+	smartAttrCurrentPendingSector   = 197
+)
+
+// NVMe admin command constants (NVMe Base Specification), shared by the
+// Windows and Linux NVMe passthrough implementations. Linux fills the
+// opcode and log ID into an NVME_IOCTL_ADMIN_CMD command directly;
+// Windows reaches Get Log Page through IOCTL_STORAGE_QUERY_PROPERTY,
+// which takes only the log ID.
+const (
+	nvmeAdminGetLogPage    = 0x02 // Get Log Page admin opcode
+	nvmeLogPageSMARTHealth = 0x02 // Log ID 0x02: SMART/Health Information
+)
+
+// smartAttrRaw finds attribute id in a 512-byte SMART READ DATA response
+// and returns its 6-byte raw value as a little-endian uint64. The
+// attribute table starts at offset 2 and each entry is 12 bytes; an ID of
+// 0 marks an unused slot.
+func smartAttrRaw(data [512]byte, id byte) (uint64, bool) {
+	const (
+		tableOffset = 2
+		entrySize   = 12
+		rawOffset   = 5
+		rawLen      = 6
+	)
+	for off := tableOffset; off+entrySize <= len(data); off += entrySize {
+		if data[off] != id {
+			continue
+		}
+		var raw uint64
+		for b := 0; b < rawLen; b++ {
+			raw |= uint64(data[off+rawOffset+b]) << (8 * b)
+		}
+		return raw, true
+	}
+	return 0, false
+}
@@ -0,0 +1,13 @@
+//go:build darwin
+
+package collector
+
+// queryDriveHealth has no macOS implementation yet: the SG_IO and NVMe
+// admin passthrough ioctls health_linux.go builds on are Linux-specific,
+// and macOS's equivalent (IOKit's IOATAStorage/IONVMeSMART user clients)
+// needs its own Cgo bridge. Returning an empty result rather than an
+// error lets the health panel degrade gracefully instead of showing
+// fabricated data.
+func queryDriveHealth() ([]DiskHealth, error) {
+	return nil, nil
+}
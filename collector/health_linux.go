@@ -0,0 +1,194 @@
+//go:build linux
+
+package collector
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// SG_IO / ATA_16 passthrough constants (Linux <scsi/sg.h>, ATA-8 ACS).
+const (
+	sgIOIoctl      = 0x2285 // SG_IO
+	sgDxferFromDev = 3      // SG_DXFER_FROM_DEV
+	sgTimeoutMs    = 3000
+
+	ata16CDBLen         = 16
+	ata16Opcode         = 0x85   // ATA PASS-THROUGH (16)
+	ata16ProtoPIODataIn = 4 << 1 // PIO Data-In protocol, CDB byte 1
+	ata16Flags          = 1<<3 | 1<<2 | 2
+	// ^ T_DIR (from device) | BYTE_BLOCK | T_LENGTH=sector count, CDB byte 2
+)
+
+// sgIOHdr mirrors struct sg_io_hdr_t.
+type sgIOHdr struct {
+	InterfaceID    int32
+	DxferDirection int32
+	CmdLen         uint8
+	MxSbLen        uint8
+	IovecCount     uint16
+	DxferLen       uint32
+	Dxferp         uintptr
+	Cmdp           uintptr
+	Sbp            uintptr
+	Timeout        uint32
+	Flags          uint32
+	PackID         int32
+	UsrPtr         uintptr
+	Status         uint8
+	MaskedStatus   uint8
+	MsgStatus      uint8
+	SbLenWr        uint8
+	HostStatus     uint16
+	DriverStatus   uint16
+	Resid          int32
+	Duration       uint32
+	Info           uint32
+}
+
+// ataSMARTReadData issues SMART READ DATA (0xB0/0xD0) through an ATA_16
+// passthrough CDB over SG_IO and returns the raw 512-byte attribute table.
+func ataSMARTReadData(fd int) ([512]byte, error) {
+	var data [512]byte
+	cdb := [ata16CDBLen]byte{
+		ata16Opcode,
+		ata16ProtoPIODataIn,
+		ata16Flags,
+		0, ataFeatureSMARTReadData, // FEATURES (15:8, 7:0)
+		0, 1, // SECTOR COUNT
+		0, 0, // LBA LOW
+		0, 0x4F, // LBA MID: 0xC24F SMART signature
+		0, 0xC2, // LBA HIGH
+		0,           // DEVICE
+		ataCmdSMART, // COMMAND
+		0,           // CONTROL
+	}
+
+	hdr := sgIOHdr{
+		InterfaceID:    'S',
+		DxferDirection: sgDxferFromDev,
+		CmdLen:         ata16CDBLen,
+		DxferLen:       uint32(len(data)),
+		Dxferp:         uintptr(unsafe.Pointer(&data[0])),
+		Cmdp:           uintptr(unsafe.Pointer(&cdb[0])),
+		Timeout:        sgTimeoutMs,
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), sgIOIoctl, uintptr(unsafe.Pointer(&hdr))); errno != 0 {
+		return data, errno
+	}
+	return data, nil
+}
+
+// NVMe admin passthrough (Linux uapi/linux/nvme_ioctl.h).
+const nvmeIoctlAdminCmd = 0xC0484E41 // NVME_IOCTL_ADMIN_CMD
+
+// nvmePassthruCmd mirrors struct nvme_passthru_cmd.
+type nvmePassthruCmd struct {
+	Opcode      uint8
+	Flags       uint8
+	Rsvd1       uint16
+	Nsid        uint32
+	Cdw2        uint32
+	Cdw3        uint32
+	Metadata    uint64
+	Addr        uint64
+	MetadataLen uint32
+	DataLen     uint32
+	Cdw10       uint32
+	Cdw11       uint32
+	Cdw12       uint32
+	Cdw13       uint32
+	Cdw14       uint32
+	Cdw15       uint32
+	TimeoutMs   uint32
+	Result      uint32
+}
+
+// nvmeGetHealthLogPage issues Get Log Page (opcode nvmeAdminGetLogPage,
+// Log ID nvmeLogPageSMARTHealth) through NVME_IOCTL_ADMIN_CMD and returns
+// the raw 512-byte SMART/Health Information log.
+func nvmeGetHealthLogPage(fd int) ([512]byte, error) {
+	var data [512]byte
+	const numDwords = len(data) / 4
+	cmd := nvmePassthruCmd{
+		Opcode:    nvmeAdminGetLogPage,
+		Nsid:      0xFFFFFFFF, // controller-wide log, not namespace-specific
+		Addr:      uint64(uintptr(unsafe.Pointer(&data[0]))),
+		DataLen:   uint32(len(data)),
+		Cdw10:     uint32(nvmeLogPageSMARTHealth) | uint32(numDwords-1)<<16,
+		TimeoutMs: sgTimeoutMs,
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), nvmeIoctlAdminCmd, uintptr(unsafe.Pointer(&cmd))); errno != 0 {
+		return data, errno
+	}
+	return data, nil
+}
+
+// queryOneDrive opens dev and tries the passthrough appropriate to its
+// kind. A drive that can be opened but won't answer the passthrough
+// (typically missing CAP_SYS_RAWIO) is still reported, just with
+// Available: false, rather than dropped from the panel.
+func queryOneDrive(dev string) DiskHealth {
+	health := DiskHealth{Device: dev, NVMe: strings.Contains(dev, "nvme")}
+
+	f, err := os.OpenFile(dev, os.O_RDONLY, 0)
+	if err != nil {
+		return health
+	}
+	defer f.Close()
+	fd := int(f.Fd())
+
+	if health.NVMe {
+		data, err := nvmeGetHealthLogPage(fd)
+		if err != nil {
+			return health
+		}
+		health.Available = true
+		health.TemperatureC = int(binary.LittleEndian.Uint16(data[1:3])) - 273
+		health.PercentageUsed = data[5]
+		health.MediaErrors = binary.LittleEndian.Uint64(data[160:168])
+		return health
+	}
+
+	data, err := ataSMARTReadData(fd)
+	if err != nil {
+		return health
+	}
+	health.Available = true
+	if raw, ok := smartAttrRaw(data, smartAttrTemperature); ok {
+		health.TemperatureC = int(raw & 0xFF)
+	}
+	if raw, ok := smartAttrRaw(data, smartAttrReallocatedSectorCount); ok {
+		health.ReallocatedCount = raw
+	}
+	if raw, ok := smartAttrRaw(data, smartAttrCurrentPendingSector); ok {
+		health.PendingCount = raw
+	}
+	return health
+}
+
+// queryDriveHealth enumerates whole-disk ATA (/dev/sdX) and NVMe
+// (/dev/nvmeNn1) block devices, skipping partitions.
+func queryDriveHealth() ([]DiskHealth, error) {
+	var devices []string
+	if ata, err := filepath.Glob("/dev/sd[a-z]"); err == nil {
+		devices = append(devices, ata...)
+	}
+	if nvme, err := filepath.Glob("/dev/nvme[0-9]n[0-9]"); err == nil {
+		devices = append(devices, nvme...)
+	}
+	sort.Strings(devices)
+
+	out := make([]DiskHealth, 0, len(devices))
+	for _, dev := range devices {
+		out = append(out, queryOneDrive(dev))
+	}
+	return out, nil
+}
@@ -0,0 +1,182 @@
+//go:build windows
+
+package collector
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// maxPhysicalDrives bounds \\.\PhysicalDriveN enumeration; Windows has no
+// single API to list them, so we probe sequential indexes until CreateFile
+// fails.
+const maxPhysicalDrives = 16
+
+// IOCTL_ATA_PASS_THROUGH (winioctl.h).
+const ioctlATAPassThrough = 0x4D02C
+
+const (
+	ataFlagDRDYRequired = 0x01
+	ataFlagDataIn       = 0x02
+)
+
+// ataPassThroughEx mirrors ATA_PASS_THROUGH_EX (ntddscsi.h).
+type ataPassThroughEx struct {
+	Length             uint16
+	AtaFlags           uint16
+	PathID             uint8
+	TargetID           uint8
+	Lun                uint8
+	ReservedAsUchar    uint8
+	DataTransferLength uint32
+	TimeOutValue       uint32
+	ReservedAsUlong    uint32
+	DataBufferOffset   uint64
+	PreviousTaskFile   [8]byte
+	CurrentTaskFile    [8]byte
+}
+
+// ataSMARTReadData issues SMART READ DATA (0xB0/0xD0) through
+// IOCTL_ATA_PASS_THROUGH and returns the raw 512-byte attribute table.
+func ataSMARTReadData(h windows.Handle) ([512]byte, error) {
+	var req struct {
+		hdr  ataPassThroughEx
+		data [512]byte
+	}
+	req.hdr.Length = uint16(unsafe.Sizeof(req.hdr))
+	req.hdr.AtaFlags = ataFlagDataIn | ataFlagDRDYRequired
+	req.hdr.DataTransferLength = uint32(len(req.data))
+	req.hdr.TimeOutValue = 3
+	req.hdr.DataBufferOffset = uint64(unsafe.Offsetof(req.data))
+	req.hdr.CurrentTaskFile[0] = ataFeatureSMARTReadData // FEATURES
+	req.hdr.CurrentTaskFile[1] = 1                       // SECTOR COUNT
+	req.hdr.CurrentTaskFile[3] = 0x4F                    // LBA MID: 0xC24F SMART signature
+	req.hdr.CurrentTaskFile[4] = 0xC2                    // LBA HIGH
+	req.hdr.CurrentTaskFile[6] = ataCmdSMART             // COMMAND
+
+	var returned uint32
+	err := windows.DeviceIoControl(h, ioctlATAPassThrough,
+		(*byte)(unsafe.Pointer(&req)), uint32(unsafe.Sizeof(req)),
+		(*byte)(unsafe.Pointer(&req)), uint32(unsafe.Sizeof(req)),
+		&returned, nil)
+	return req.data, err
+}
+
+// IOCTL_STORAGE_QUERY_PROPERTY (winioctl.h), used here to reach NVMe's Get
+// Log Page admin command without a driver-specific NVMe IOCTL.
+const ioctlStorageQueryProperty = 0x2D1400
+
+const (
+	storagePropertyIDProtocolSpecific = 50 // StorageDeviceProtocolSpecificProperty
+	storageQueryTypeStandard          = 0
+
+	storageProtocolTypeNVMe    = 3
+	storageProtocolDataTypeLog = 2 // NVMeDataTypeLogPage
+)
+
+// storagePropertyQuery mirrors STORAGE_PROPERTY_QUERY's fixed header.
+type storagePropertyQuery struct {
+	PropertyID uint32
+	QueryType  uint32
+}
+
+// storageProtocolSpecificData mirrors STORAGE_PROTOCOL_SPECIFIC_DATA.
+type storageProtocolSpecificData struct {
+	ProtocolType         uint32
+	DataType             uint32
+	ProtocolDataValue    uint32
+	ProtocolDataSubValue uint32
+	ProtocolDataOffset   uint32
+	ProtocolDataLength   uint32
+}
+
+// nvmeGetHealthLogPage issues Get Log Page (opcode nvmeAdminGetLogPage,
+// Log ID nvmeLogPageSMARTHealth) through IOCTL_STORAGE_QUERY_PROPERTY and
+// returns the raw 512-byte SMART/Health Information log.
+func nvmeGetHealthLogPage(h windows.Handle) ([512]byte, error) {
+	var req struct {
+		query storagePropertyQuery
+		proto storageProtocolSpecificData
+		data  [512]byte
+	}
+	req.query.PropertyID = storagePropertyIDProtocolSpecific
+	req.query.QueryType = storageQueryTypeStandard
+	req.proto.ProtocolType = storageProtocolTypeNVMe
+	req.proto.DataType = storageProtocolDataTypeLog
+	req.proto.ProtocolDataValue = nvmeLogPageSMARTHealth
+	req.proto.ProtocolDataOffset = uint32(unsafe.Offsetof(req.data))
+	req.proto.ProtocolDataLength = uint32(len(req.data))
+
+	var returned uint32
+	err := windows.DeviceIoControl(h, ioctlStorageQueryProperty,
+		(*byte)(unsafe.Pointer(&req)), uint32(unsafe.Sizeof(req)),
+		(*byte)(unsafe.Pointer(&req)), uint32(unsafe.Sizeof(req)),
+		&returned, nil)
+	return req.data, err
+}
+
+// queryOneDrive opens a physical drive and tries ATA SMART first, then
+// falls back to NVMe's Get Log Page. A drive that answers neither (no
+// admin rights, or a controller that rejects both) is still reported,
+// just with Available: false, so the panel shows it as unknown rather
+// than dropping it silently.
+func queryOneDrive(path string) (DiskHealth, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return DiskHealth{}, err
+	}
+	h, err := windows.CreateFile(p, windows.GENERIC_READ|windows.GENERIC_WRITE,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE, nil, windows.OPEN_EXISTING, 0, 0)
+	if err == windows.ERROR_ACCESS_DENIED {
+		return DiskHealth{Device: path}, nil // drive exists, but no admin rights to open it
+	}
+	if err != nil {
+		return DiskHealth{}, err // no such drive: enumeration should stop
+	}
+	defer windows.CloseHandle(h)
+
+	health := DiskHealth{Device: path}
+
+	if data, err := ataSMARTReadData(h); err == nil {
+		health.Available = true
+		if raw, ok := smartAttrRaw(data, smartAttrTemperature); ok {
+			health.TemperatureC = int(raw & 0xFF)
+		}
+		if raw, ok := smartAttrRaw(data, smartAttrReallocatedSectorCount); ok {
+			health.ReallocatedCount = raw
+		}
+		if raw, ok := smartAttrRaw(data, smartAttrCurrentPendingSector); ok {
+			health.PendingCount = raw
+		}
+		return health, nil
+	}
+
+	if data, err := nvmeGetHealthLogPage(h); err == nil {
+		health.NVMe = true
+		health.Available = true
+		health.TemperatureC = int(binary.LittleEndian.Uint16(data[1:3])) - 273
+		health.PercentageUsed = data[5]
+		health.MediaErrors = binary.LittleEndian.Uint64(data[160:168])
+		return health, nil
+	}
+
+	return health, nil
+}
+
+// queryDriveHealth probes \\.\PhysicalDrive0.. until CreateFile fails,
+// since Windows has no API that just returns how many physical drives
+// exist.
+func queryDriveHealth() ([]DiskHealth, error) {
+	var out []DiskHealth
+	for i := 0; i < maxPhysicalDrives; i++ {
+		health, err := queryOneDrive(fmt.Sprintf(`\\.\PhysicalDrive%d`, i))
+		if err != nil {
+			break
+		}
+		out = append(out, health)
+	}
+	return out, nil
+}
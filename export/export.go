@@ -0,0 +1,31 @@
+// Package export implements sampler.Exporter backends selected by the
+// --export flag: a Prometheus /metrics endpoint and an NDJSON stream.
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gsmitheidw/iotop-w/sampler"
+)
+
+// New builds the Exporter described by spec, one of:
+//
+//	prometheus:<addr>   serve /metrics on addr, e.g. prometheus::9090
+//	json:-               write one NDJSON object per tick to stdout
+//	json:<path>          write one NDJSON object per tick to path
+func New(spec string) (sampler.Exporter, error) {
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("export: %q must be of the form kind:target", spec)
+	}
+
+	switch kind {
+	case "prometheus":
+		return newPrometheusExporter(arg)
+	case "json":
+		return newJSONExporter(arg)
+	default:
+		return nil, fmt.Errorf("export: unknown kind %q (want prometheus or json)", kind)
+	}
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gsmitheidw/iotop-w/sampler"
+)
+
+// jsonExporter writes one NDJSON object per Export call.
+type jsonExporter struct {
+	w      io.Writer
+	closer io.Closer // nil for stdout
+}
+
+func newJSONExporter(target string) (sampler.Exporter, error) {
+	if target == "-" || target == "" {
+		return &jsonExporter{w: os.Stdout}, nil
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("export: opening %s: %w", target, err)
+	}
+	return &jsonExporter{w: f, closer: f}, nil
+}
+
+type jsonSample struct {
+	Timestamp string            `json:"timestamp"`
+	DiskQueue float64           `json:"disk_queue"`
+	Processes []jsonProcessRate `json:"processes"`
+}
+
+type jsonProcessRate struct {
+	PID   uint32  `json:"pid"`
+	Name  string  `json:"name"`
+	Read  float64 `json:"read_bytes_per_sec"`
+	Write float64 `json:"write_bytes_per_sec"`
+	Total float64 `json:"total_bytes_per_sec"`
+}
+
+func (e *jsonExporter) Export(s sampler.Sample) error {
+	out := jsonSample{
+		Timestamp: s.Timestamp.Format("2006-01-02T15:04:05.000Z07:00"),
+		DiskQueue: s.DiskQueue,
+		Processes: make([]jsonProcessRate, len(s.Rates)),
+	}
+	for i, r := range s.Rates {
+		out.Processes[i] = jsonProcessRate{
+			PID: r.PID, Name: r.Name, Read: r.Read, Write: r.Write, Total: r.Total,
+		}
+	}
+
+	enc := json.NewEncoder(e.w)
+	return enc.Encode(out)
+}
+
+func (e *jsonExporter) Close() error {
+	if e.closer != nil {
+		return e.closer.Close()
+	}
+	return nil
+}
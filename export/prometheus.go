@@ -0,0 +1,81 @@
+package export
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gsmitheidw/iotop-w/sampler"
+)
+
+// prometheusExporter serves the most recent Sample as Prometheus text
+// exposition format on /metrics. It's hand-rolled rather than pulling in
+// client_golang: three gauge families is not worth the dependency.
+type prometheusExporter struct {
+	mu     sync.Mutex
+	latest sampler.Sample
+	srv    *http.Server
+}
+
+func newPrometheusExporter(addr string) (sampler.Exporter, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("export: prometheus: needs an address, e.g. prometheus::9090")
+	}
+
+	e := &prometheusExporter{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.handleMetrics)
+	e.srv = &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := e.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintln(os.Stderr, "iotop-w: prometheus exporter stopped:", err)
+		}
+	}()
+
+	return e, nil
+}
+
+func (e *prometheusExporter) Export(s sampler.Sample) error {
+	// s.Rates aliases the caller's slice, which the TUI filters/sorts in
+	// place on every tick; copy it so handleMetrics never reads a slice
+	// that's being reordered out from under it on another goroutine.
+	s.Rates = append([]sampler.Rate(nil), s.Rates...)
+	e.mu.Lock()
+	e.latest = s
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *prometheusExporter) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	s := e.latest
+	e.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP iotopw_process_read_bytes_per_second Per-process disk read rate.\n")
+	b.WriteString("# TYPE iotopw_process_read_bytes_per_second gauge\n")
+	for _, p := range s.Rates {
+		fmt.Fprintf(&b, "iotopw_process_read_bytes_per_second{pid=\"%d\",name=%q} %g\n", p.PID, p.Name, p.Read)
+	}
+
+	b.WriteString("# HELP iotopw_process_write_bytes_per_second Per-process disk write rate.\n")
+	b.WriteString("# TYPE iotopw_process_write_bytes_per_second gauge\n")
+	for _, p := range s.Rates {
+		fmt.Fprintf(&b, "iotopw_process_write_bytes_per_second{pid=\"%d\",name=%q} %g\n", p.PID, p.Name, p.Write)
+	}
+
+	b.WriteString("# HELP iotopw_disk_queue_length Disk pressure metric (queue length on Windows, a comparable ratio elsewhere).\n")
+	b.WriteString("# TYPE iotopw_disk_queue_length gauge\n")
+	fmt.Fprintf(&b, "iotopw_disk_queue_length %g\n", s.DiskQueue)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write([]byte(b.String()))
+}
+
+func (e *prometheusExporter) Close() error {
+	return e.srv.Close()
+}
@@ -4,13 +4,17 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
-	"unsafe"
 
-	"golang.org/x/sys/windows"
+	"github.com/gsmitheidw/iotop-w/collector"
+	"github.com/gsmitheidw/iotop-w/export"
+	"github.com/gsmitheidw/iotop-w/panel"
+	"github.com/gsmitheidw/iotop-w/sampler"
 )
 
 // ----------------------- VERSION -----------------------
@@ -24,11 +28,12 @@ const (
 
 // ----------------------- SETTINGS -----------------------
 const (
-	historyWidth    = 30
-	minInterval     = 100 * time.Millisecond
-	maxInterval     = 10 * time.Second
-	queueSaturation = 2.0
-	maxNameLen      = 16 // truncate long process names
+	historyWidth         = 30
+	minInterval          = 100 * time.Millisecond
+	maxInterval          = 10 * time.Second
+	queueSaturation      = 2.0
+	maxNameLen           = 16 // truncate long process names
+	dashboardPanelHeight = 6  // total rows of the --dashboard grid, split across its panel rows
 )
 
 // ----------------------- VISUALIZATION MODES -----------------------
@@ -201,92 +206,6 @@ func nextInterval(curr time.Duration, up bool) time.Duration {
 	return curr // do not jump if curr is not exact
 }
 
-// disableEcho turns off line input & echo, returns restore function
-func disableEcho() func() {
-	h, _ := windows.GetStdHandle(windows.STD_INPUT_HANDLE)
-	var mode uint32
-	windows.GetConsoleMode(h, &mode)
-	orig := mode
-	mode &^= windows.ENABLE_ECHO_INPUT | windows.ENABLE_LINE_INPUT
-	windows.SetConsoleMode(h, mode)
-	return func() { windows.SetConsoleMode(h, orig) }
-}
-
-// ----------------------- WIN32 / PDH -----------------------
-var (
-	k32 = windows.NewLazySystemDLL("kernel32.dll")
-	pdh = windows.NewLazySystemDLL("pdh.dll")
-
-	pGetIOCounters                 = k32.NewProc("GetProcessIoCounters")
-	pGetNumberOfConsoleInputEvents = k32.NewProc("GetNumberOfConsoleInputEvents")
-	pReadConsoleInputW             = k32.NewProc("ReadConsoleInputW")
-
-	pPdhOpenQuery  = pdh.NewProc("PdhOpenQueryW")
-	pPdhAddCounter = pdh.NewProc("PdhAddEnglishCounterW")
-	pPdhCollect    = pdh.NewProc("PdhCollectQueryData")
-	pPdhGetValue   = pdh.NewProc("PdhGetFormattedCounterValue")
-	pPdhCloseQuery = pdh.NewProc("PdhCloseQuery")
-)
-
-// ----------------------- CONSOLE INPUT EVENTS -----------------------
-const (
-	KEY_EVENT = 0x0001
-)
-
-type inputRecord struct {
-	EventType uint16
-	_         [2]byte
-	Event     [16]byte
-}
-
-type keyEventRecord struct {
-	KeyDown         int32
-	RepeatCount     uint16
-	VirtualKeyCode  uint16
-	VirtualScanCode uint16
-	Char            uint16
-	ControlKeyState uint32
-}
-
-// readConsoleKey checks for key presses via console input events
-func readConsoleKey() (rune, bool) {
-	h, err := windows.GetStdHandle(windows.STD_INPUT_HANDLE)
-	if err != nil {
-		return 0, false
-	}
-
-	var numEvents uint32
-	r, _, _ := pGetNumberOfConsoleInputEvents.Call(uintptr(h), uintptr(unsafe.Pointer(&numEvents)))
-	if r == 0 || numEvents == 0 {
-		return 0, false
-	}
-
-	records := make([]inputRecord, 1)
-	var read uint32
-	r, _, _ = pReadConsoleInputW.Call(
-		uintptr(h),
-		uintptr(unsafe.Pointer(&records[0])),
-		1,
-		uintptr(unsafe.Pointer(&read)),
-	)
-	if r == 0 || read == 0 {
-		return 0, false
-	}
-
-	rec := records[0]
-	if rec.EventType != KEY_EVENT {
-		return 0, false
-	}
-
-	// Parse key event
-	keyEvent := (*keyEventRecord)(unsafe.Pointer(&rec.Event[0]))
-	if keyEvent.KeyDown == 0 {
-		return 0, false // only care about key down
-	}
-
-	return rune(keyEvent.Char), true
-}
-
 // ----------------------- TYPES -----------------------
 type ProcHist struct {
 	Read, Write Ring
@@ -294,99 +213,139 @@ type ProcHist struct {
 	LastSeen    time.Time
 }
 
-type ProcIO struct {
-	PID   uint32
-	Name  string
-	Read  uint64
-	Write uint64
-}
-
-type Snapshot struct {
-	Data      map[uint32]ProcIO
-	Timestamp time.Time
-}
-
-type Rates struct {
-	PID   uint32
-	Name  string
-	Read  float64 // bytes/sec
-	Write float64 // bytes/sec
-	Total float64 // bytes/sec
-}
+// SortKey picks which field rates are ordered by, cycled with 'o'.
+type SortKey int
 
-type DiskQueue struct {
-	query   uintptr
-	counter uintptr
-}
-
-// ----------------------- PROCESS HANDLE CACHE -----------------------
-type HandleCache struct {
-	handles map[uint32]windows.Handle
-}
-
-func newHandleCache() *HandleCache {
-	return &HandleCache{handles: make(map[uint32]windows.Handle)}
-}
-
-func (hc *HandleCache) get(pid uint32) (windows.Handle, error) {
-	if h, exists := hc.handles[pid]; exists {
-		return h, nil
-	}
-	// Try to open the handle
-	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
-	if err != nil {
-		return 0, err
-	}
-	hc.handles[pid] = h
-	return h, nil
-}
+const (
+	SortTotal SortKey = iota
+	SortRead
+	SortWrite
+	SortPID
+	SortName
+	numSortKeys
+)
 
-func (hc *HandleCache) close(pid uint32) {
-	if h, exists := hc.handles[pid]; exists {
-		windows.CloseHandle(h)
-		delete(hc.handles, pid)
+func (k SortKey) String() string {
+	switch k {
+	case SortRead:
+		return "Read"
+	case SortWrite:
+		return "Write"
+	case SortPID:
+		return "PID"
+	case SortName:
+		return "Name"
+	default:
+		return "Total"
 	}
 }
 
-func (hc *HandleCache) closeAll() {
-	for pid, h := range hc.handles {
-		windows.CloseHandle(h)
-		delete(hc.handles, pid)
+// ViewState holds the interactive filter/sort/pause state that's layered
+// on top of a Sample before it's rendered: a name regex (`/`), a username
+// filter ('u'), a sort key ('o', cycling SortKey), and whether sampling
+// is currently paused (space). inputMode/inputBuf track an in-progress
+// `/` or `u` prompt; both are empty outside of one.
+type ViewState struct {
+	NameFilter    *regexp.Regexp
+	NameFilterRaw string
+	UserFilter    string
+	Sort          SortKey
+	Paused        bool
+
+	inputMode     string // "", "filter", or "user"
+	inputBuf      string
+	filterInvalid bool // last /filter entry didn't compile; NameFilter[Raw] unchanged
+}
+
+// apply filters and sorts rates in place, returning the subset to
+// display.
+func (v *ViewState) apply(rates []sampler.Rate) []sampler.Rate {
+	out := rates[:0]
+	for _, r := range rates {
+		if v.NameFilter != nil && !v.NameFilter.MatchString(r.Name) {
+			continue
+		}
+		if v.UserFilter != "" && r.User != v.UserFilter {
+			continue
+		}
+		out = append(out, r)
 	}
-}
 
-func (hc *HandleCache) cleanup(validPIDs map[uint32]bool) {
-	for pid, h := range hc.handles {
-		if !validPIDs[pid] {
-			windows.CloseHandle(h)
-			delete(hc.handles, pid)
+	sort.Slice(out, func(i, j int) bool {
+		switch v.Sort {
+		case SortRead:
+			return out[i].Read > out[j].Read
+		case SortWrite:
+			return out[i].Write > out[j].Write
+		case SortPID:
+			return out[i].PID < out[j].PID
+		case SortName:
+			return out[i].Name < out[j].Name
+		default:
+			return out[i].Total > out[j].Total
+		}
+	})
+	return out
+}
+
+// handleInputKey feeds one keystroke into an in-progress `/` or `u`
+// prompt: Enter commits, Esc cancels, backspace edits, anything else is
+// appended.
+func (v *ViewState) handleInputKey(ch rune) {
+	switch ch {
+	case '\r', '\n':
+		switch v.inputMode {
+		case "filter":
+			if v.inputBuf == "" {
+				v.NameFilter = nil
+				v.NameFilterRaw = ""
+				v.filterInvalid = false
+			} else if re, err := regexp.Compile(v.inputBuf); err == nil {
+				v.NameFilter = re
+				v.NameFilterRaw = v.inputBuf
+				v.filterInvalid = false
+			} else {
+				// Leave the previously applied filter in place; just flag
+				// that this entry didn't compile so the status bar doesn't
+				// claim it's active.
+				v.filterInvalid = true
+			}
+		case "user":
+			v.UserFilter = v.inputBuf
+		}
+		v.inputMode = ""
+		v.inputBuf = ""
+	case 27: // Esc
+		v.inputMode = ""
+		v.inputBuf = ""
+	case 8, 127: // Backspace
+		if len(v.inputBuf) > 0 {
+			v.inputBuf = v.inputBuf[:len(v.inputBuf)-1]
+		}
+	default:
+		if ch >= 32 && ch < 127 {
+			v.inputBuf += string(ch)
 		}
 	}
 }
 
-// ----------------------- DISK QUEUE -----------------------
-func newDiskQueue() *DiskQueue {
-	var q uintptr
-	pPdhOpenQuery.Call(0, 0, uintptr(unsafe.Pointer(&q)))
-	path, _ := windows.UTF16PtrFromString(`\PhysicalDisk(_Total)\Avg. Disk Queue Length`)
-	var c uintptr
-	pPdhAddCounter.Call(q, uintptr(unsafe.Pointer(path)), 0, uintptr(unsafe.Pointer(&c)))
-	pPdhCollect.Call(q)
-	return &DiskQueue{query: q, counter: c}
-}
-
-func (d *DiskQueue) read() float64 {
-	pPdhCollect.Call(d.query)
-	var val struct {
-		CStatus     uint32
-		DoubleValue float64
+// statusLine summarizes the active filter/sort/pause state for the
+// status bar.
+func (v *ViewState) statusLine() string {
+	s := fmt.Sprintf("Sort: %s", v.Sort)
+	if v.NameFilterRaw != "" {
+		s += fmt.Sprintf("  Filter: /%s/", v.NameFilterRaw)
 	}
-	pPdhGetValue.Call(d.counter, 0x00000200, 0, uintptr(unsafe.Pointer(&val)))
-	return val.DoubleValue
-}
-
-func (d *DiskQueue) close() {
-	pPdhCloseQuery.Call(d.query)
+	if v.filterInvalid {
+		s += "  [invalid filter regex]"
+	}
+	if v.UserFilter != "" {
+		s += fmt.Sprintf("  User: %s", v.UserFilter)
+	}
+	if v.Paused {
+		s += "  [PAUSED]"
+	}
+	return s
 }
 
 // ----------------------- DISK BAR -----------------------
@@ -408,49 +367,57 @@ func renderDiskBar(depth float64, width int, maxQueue float64) string {
 			bar[i] = Base03 + "█" + Reset
 		}
 	}
-	
+
 	// Clean bar only - no confusing numbers
 	return fmt.Sprintf("%sDisk Pressure:%s %s", Blue, Reset, strings.Join(bar, ""))
 }
 
-// ----------------------- PROCESS SNAPSHOT -----------------------
-func snapshotIO(cache *HandleCache) Snapshot {
-	out := make(map[uint32]ProcIO)
-	snap, _ := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
-	defer windows.CloseHandle(snap)
+// newDashboardGrid wires a panel per metric the collector exposes: CPU
+// per-core, memory gauge, network rx/tx, per-physical-disk throughput
+// rows, and per-drive SMART health.
+func newDashboardGrid(c collector.Collector) *panel.Grid {
+	g := panel.NewGrid()
+	g.Add("CPU", panel.NewCPUPanel(func() ([]float64, error) {
+		s, err := c.CPU()
+		return s.PerCore, err
+	}))
+	g.Add("Memory", panel.NewMemPanel(func() (uint64, uint64, error) {
+		s, err := c.Memory()
+		return s.Total, s.Available, err
+	}))
+	g.Add("Network", panel.NewNetPanel(func() (float64, float64, error) {
+		s, err := c.Network()
+		return s.RxBytesPerSec, s.TxBytesPerSec, err
+	}))
+	g.Add("Disks", panel.NewDiskPanel(c.Disks))
+	g.Add("Disk Health", panel.NewDiskHealthPanel(c.Health))
+	return g
+}
+
+// runHeadless drives the sampler/exporter pipeline with no TUI at all,
+// for server and agent deployments that only care about --export output.
+// It runs until interrupted (Ctrl-C / SIGTERM).
+func runHeadless(smp *sampler.Sampler, exporters []sampler.Exporter, interval time.Duration) {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
 
-	var pe windows.ProcessEntry32
-	pe.Size = uint32(unsafe.Sizeof(pe))
-	if windows.Process32First(snap, &pe) != nil {
-		return Snapshot{Data: out, Timestamp: time.Now()}
-	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	validPIDs := make(map[uint32]bool)
 	for {
-		validPIDs[pe.ProcessID] = true
-
-		h, err := cache.get(pe.ProcessID)
-		if err == nil {
-			var io windows.IO_COUNTERS
-			if r, _, _ := pGetIOCounters.Call(uintptr(h), uintptr(unsafe.Pointer(&io))); r != 0 {
-				out[pe.ProcessID] = ProcIO{
-					PID:   pe.ProcessID,
-					Name:  windows.UTF16ToString(pe.ExeFile[:]),
-					Read:  io.ReadTransferCount,
-					Write: io.WriteTransferCount,
-				}
+		select {
+		case <-ticker.C:
+			sample, err := smp.Sample()
+			if err != nil {
+				continue
 			}
-		}
-
-		if windows.Process32Next(snap, &pe) != nil {
-			break
+			for _, exp := range exporters {
+				exp.Export(sample)
+			}
+		case <-stop:
+			return
 		}
 	}
-
-	// Clean up handles for PIDs that no longer exist
-	cache.cleanup(validPIDs)
-
-	return Snapshot{Data: out, Timestamp: time.Now()}
 }
 
 // ----------------------- MAIN -----------------------
@@ -459,7 +426,10 @@ func main() {
 	help := false
 	showVersion := false
 	showInfo := false
+	dashboard := false
+	noTUI := false
 	visualMode := ModeBraille // default to braille
+	var exportSpecs []string
 
 	for i := 1; i < len(os.Args); i++ {
 		switch {
@@ -469,8 +439,19 @@ func main() {
 			showVersion = true
 		case os.Args[i] == "--info" || os.Args[i] == "-i":
 			showInfo = true
+		case os.Args[i] == "--dashboard" || os.Args[i] == "-d":
+			dashboard = true
+		case os.Args[i] == "--no-tui":
+			noTUI = true
 		case os.Args[i] == "--blocks" || os.Args[i] == "-b":
 			visualMode = ModeBlocks
+		case os.Args[i] == "--export":
+			if i+1 < len(os.Args) {
+				exportSpecs = append(exportSpecs, os.Args[i+1])
+				i++
+			}
+		case strings.HasPrefix(os.Args[i], "--export="):
+			exportSpecs = append(exportSpecs, strings.TrimPrefix(os.Args[i], "--export="))
 		case os.Args[i] == "--top":
 			if i+1 < len(os.Args) {
 				n, err := strconv.Atoi(os.Args[i+1])
@@ -505,6 +486,12 @@ Options:
   --info, -i       Show repo info and license
   --top <number>   Show top <number> processes (max %d)
   --blocks, -b     Use block-style visualization (default: braille)
+  --dashboard, -d  Show CPU/memory/network/disk panels above the table
+  --export <spec>  Export samples; repeatable. <spec> is one of:
+                     prometheus:<addr>  serve /metrics, e.g. prometheus::9090
+                     json:-             NDJSON to stdout
+                     json:<path>        NDJSON appended to a file
+  --no-tui         Run headless: sample and export without drawing a TUI
 `, Version, MaxTopN)
 		return
 	}
@@ -519,31 +506,53 @@ Options:
 		return
 	}
 
-	h, _ := windows.GetStdHandle(windows.STD_OUTPUT_HANDLE)
-	var mode uint32
-	windows.GetConsoleMode(h, &mode)
-	windows.SetConsoleMode(h, mode|0x0004)
+	c, err := collector.New()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "iotop-w: failed to start collector:", err)
+		os.Exit(1)
+	}
+	defer c.Close()
+
+	var exporters []sampler.Exporter
+	for _, spec := range exportSpecs {
+		exp, err := export.New(spec)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "iotop-w:", err)
+			os.Exit(1)
+		}
+		exporters = append(exporters, exp)
+	}
+	defer func() {
+		for _, exp := range exporters {
+			exp.Close()
+		}
+	}()
+
+	smp := sampler.New(c)
+	interval := 1 * time.Second // start at usable default
 
-	restore := disableEcho()
+	if noTUI {
+		runHeadless(smp, exporters, interval)
+		return
+	}
+
+	restore := platformInit()
 	defer func() {
 		restore()
-		windows.FlushConsoleInputBuffer(windows.STD_INPUT_HANDLE)
 		fmt.Print("\x1b[0m\x1b[H\x1b[J\x1b[?25h") // reset colors, clear screen & show cursor
 	}()
 	fmt.Print("\x1b[?25l")
 
-	disk := newDiskQueue()
-	defer disk.close()
-
-	cache := newHandleCache()
-	defer cache.closeAll()
+	var grid *panel.Grid
+	if dashboard {
+		grid = newDashboardGrid(c)
+	}
 
-	interval := 1 * time.Second // start at usable default
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	prevSnap := snapshotIO(cache)
 	procs := map[uint32]*ProcHist{}
+	view := &ViewState{}
 
 	for {
 		select {
@@ -551,43 +560,24 @@ Options:
 			fmt.Print("\x1b[H\x1b[J")
 			fmt.Printf("%s〘iotop-w〙 %s %s\n\n", Blue, Version, Reset)
 
-			q := disk.read()
-			fmt.Println(renderDiskBar(q, 30, queueSaturation))
-			fmt.Println()
-			
-			currSnap := snapshotIO(cache)
-			elapsed := currSnap.Timestamp.Sub(prevSnap.Timestamp).Seconds()
-
-			if elapsed <= 0 {
-				elapsed = 0.001 // prevent division by zero
-			}
-
-			var rates []Rates
-			for pid, now := range currSnap.Data {
-				old, ok := prevSnap.Data[pid]
-				if !ok {
-					continue
-				}
-				rDelta := float64(now.Read - old.Read)
-				wDelta := float64(now.Write - old.Write)
-				if rDelta+wDelta == 0 {
-					continue
+			if grid != nil {
+				grid.Update(interval)
+				w, _ := termSize()
+				for _, line := range grid.Render(w, dashboardPanelHeight) {
+					fmt.Println(line)
 				}
+				fmt.Println()
+			}
 
-				// Calculate actual rates in bytes/sec
-				rRate := rDelta / elapsed
-				wRate := wDelta / elapsed
-
-				rates = append(rates, Rates{
-					PID:   pid,
-					Name:  now.Name,
-					Read:  rRate,
-					Write: wRate,
-					Total: rRate + wRate,
-				})
+			sample, _ := smp.Sample()
+			for _, exp := range exporters {
+				exp.Export(sample)
 			}
 
-			sort.Slice(rates, func(i, j int) bool { return rates[i].Total > rates[j].Total })
+			fmt.Println(renderDiskBar(sample.DiskQueue, 30, queueSaturation))
+			fmt.Println()
+
+			rates := view.apply(sample.Rates)
 
 			fmt.Printf("%s%-5s │ %-*s │ %-*s │ %-*s%s\n",
 				Blue, "PID", maxNameLen, "Name", historyWidth, "Read", historyWidth, "Write", Reset)
@@ -635,7 +625,7 @@ Options:
 				displayName := string(nameRunes)
 
 				fmt.Printf("%-5d │ %-*s │ %-*s │ %-*s\n",
-					r.PID, maxNameLen, displayName, historyWidth, 
+					r.PID, maxNameLen, displayName, historyWidth,
 					h.Read.render(visualMode), historyWidth, h.Write.render(visualMode))
 			}
 
@@ -646,8 +636,6 @@ Options:
 				}
 			}
 
-			prevSnap = currSnap
-
 			// Display interval nicely
 			var intervalStr string
 			if interval < time.Second {
@@ -656,6 +644,15 @@ Options:
 				intervalStr = fmt.Sprintf("%.0fs", interval.Seconds())
 			}
 
+			fmt.Printf("\n%s%s%s\n", Base0, view.statusLine(), Reset)
+			if view.inputMode != "" {
+				prompt := "Filter by name (regex)"
+				if view.inputMode == "user" {
+					prompt = "Filter by user"
+				}
+				fmt.Printf("%s%s:%s %s_\n", Blue, prompt, Reset, view.inputBuf)
+			}
+
 			// Bubble tea style controls bar - properly aligned
 			fmt.Printf("\n%s╭────────────────────────────────────────────────────╮%s\n", Base0, Reset)
 			fmt.Printf("%s│%s Interval: %s%-5s%s%s│%s %s+/-%s %sAdjust%s %s│%s %ss%s %sStyle%s %s│%s %sq%s %sQuit%s     %s│%s\n",
@@ -671,25 +668,52 @@ Options:
 			fmt.Printf("%s╰────────────────────────────────────────────────────╯%s\n", Base0, Reset)
 
 		default:
-			// Check for console input events
-			if ch, ok := readConsoleKey(); ok {
-				ch = rune(strings.ToLower(string(ch))[0])
-				switch ch {
-				case 'q':
-					fmt.Print("\x1b[0m\x1b[H\x1b[J\x1b[?25h") // reset colors, clear, show cursor
-					return
-				case '+', '=':
-					interval = nextInterval(interval, true)
-					ticker.Reset(interval)
-				case '-', '_':
-					interval = nextInterval(interval, false)
-					ticker.Reset(interval)
-				case 's':
-					// Toggle visualization mode
-					if visualMode == ModeBraille {
-						visualMode = ModeBlocks
-					} else {
-						visualMode = ModeBraille
+			// Check for key presses
+			if ch, ok := readKey(); ok {
+				if view.inputMode != "" {
+					view.handleInputKey(ch)
+				} else {
+					switch lower := rune(strings.ToLower(string(ch))[0]); lower {
+					case 'q':
+						fmt.Print("\x1b[0m\x1b[H\x1b[J\x1b[?25h") // reset colors, clear, show cursor
+						return
+					case '+', '=':
+						interval = nextInterval(interval, true)
+						if !view.Paused {
+							ticker.Reset(interval)
+						}
+					case '-', '_':
+						interval = nextInterval(interval, false)
+						if !view.Paused {
+							ticker.Reset(interval)
+						}
+					case 's':
+						// Toggle visualization mode
+						if visualMode == ModeBraille {
+							visualMode = ModeBlocks
+						} else {
+							visualMode = ModeBraille
+						}
+					case '1', '2', '3', '4', '5':
+						if grid != nil {
+							grid.Toggle(int(lower - '0'))
+						}
+					case '/':
+						view.inputMode = "filter"
+						view.inputBuf = ""
+					case 'u':
+						view.inputMode = "user"
+						view.inputBuf = ""
+					case 'o':
+						view.Sort = (view.Sort + 1) % numSortKeys
+					case ' ':
+						view.Paused = !view.Paused
+						if view.Paused {
+							ticker.Stop()
+						} else {
+							smp.Reset()
+							ticker.Reset(interval)
+						}
 					}
 				}
 			}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gsmitheidw/iotop-w/sampler"
+)
+
+func TestViewStateApplyFilterSort(t *testing.T) {
+	rates := []sampler.Rate{
+		{PID: 3, Name: "bash", User: "alice", Read: 10, Write: 2, Total: 12},
+		{PID: 1, Name: "chrome", User: "bob", Read: 5, Write: 5, Total: 10},
+		{PID: 2, Name: "dockerd", User: "alice", Read: 1, Write: 100, Total: 101},
+	}
+
+	tests := []struct {
+		name string
+		view ViewState
+		want []uint32 // expected PIDs, in order
+	}{
+		{
+			name: "no filter sorts by total desc",
+			view: ViewState{},
+			want: []uint32{2, 3, 1},
+		},
+		{
+			name: "name filter",
+			view: ViewState{NameFilter: regexp.MustCompile("^chrome$")},
+			want: []uint32{1},
+		},
+		{
+			name: "user filter",
+			view: ViewState{UserFilter: "alice"},
+			want: []uint32{2, 3},
+		},
+		{
+			name: "sort by read",
+			view: ViewState{Sort: SortRead},
+			want: []uint32{3, 1, 2},
+		},
+		{
+			name: "sort by write",
+			view: ViewState{Sort: SortWrite},
+			want: []uint32{2, 1, 3},
+		},
+		{
+			name: "sort by pid",
+			view: ViewState{Sort: SortPID},
+			want: []uint32{1, 2, 3},
+		},
+		{
+			name: "sort by name",
+			view: ViewState{Sort: SortName},
+			want: []uint32{3, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := append([]sampler.Rate(nil), rates...)
+			got := tt.view.apply(in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d rates, want %d", len(got), len(tt.want))
+			}
+			for i, r := range got {
+				if r.PID != tt.want[i] {
+					t.Errorf("rate %d: got PID %d, want %d", i, r.PID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHandleInputKeyFilter(t *testing.T) {
+	feed := func(v *ViewState, s string) {
+		for _, ch := range s {
+			v.handleInputKey(ch)
+		}
+		v.handleInputKey('\r')
+	}
+
+	t.Run("valid regex commits and clears invalid flag", func(t *testing.T) {
+		v := &ViewState{inputMode: "filter", filterInvalid: true}
+		feed(v, "^chrome$")
+		if v.NameFilter == nil || v.NameFilterRaw != "^chrome$" {
+			t.Fatalf("expected filter to be applied, got NameFilter=%v NameFilterRaw=%q", v.NameFilter, v.NameFilterRaw)
+		}
+		if v.filterInvalid {
+			t.Error("filterInvalid should be cleared after a valid regex")
+		}
+	})
+
+	t.Run("invalid regex leaves prior filter active and flags invalid", func(t *testing.T) {
+		v := &ViewState{inputMode: "filter", NameFilter: regexp.MustCompile("old"), NameFilterRaw: "old"}
+		feed(v, "[")
+		if v.NameFilterRaw != "old" {
+			t.Errorf("expected prior filter to survive a bad regex, got NameFilterRaw=%q", v.NameFilterRaw)
+		}
+		if !v.filterInvalid {
+			t.Error("filterInvalid should be set after a regex that fails to compile")
+		}
+	})
+
+	t.Run("empty input clears the filter", func(t *testing.T) {
+		v := &ViewState{inputMode: "filter", NameFilter: regexp.MustCompile("old"), NameFilterRaw: "old", filterInvalid: true}
+		feed(v, "")
+		if v.NameFilter != nil || v.NameFilterRaw != "" {
+			t.Errorf("expected filter to be cleared, got NameFilter=%v NameFilterRaw=%q", v.NameFilter, v.NameFilterRaw)
+		}
+		if v.filterInvalid {
+			t.Error("filterInvalid should be cleared when the filter is cleared")
+		}
+	})
+}
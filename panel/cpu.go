@@ -0,0 +1,46 @@
+package panel
+
+import (
+	"fmt"
+	"time"
+)
+
+// CPUPanel renders one sparkline per core, 0-100%.
+type CPUPanel struct {
+	sample func() ([]float64, error)
+	rings  []*ring
+	latest []float64
+}
+
+// NewCPUPanel wraps a per-core percent sampler, e.g. collector.Collector.CPU.
+func NewCPUPanel(sample func() ([]float64, error)) *CPUPanel {
+	return &CPUPanel{sample: sample}
+}
+
+func (p *CPUPanel) Update(dt time.Duration) {
+	cores, err := p.sample()
+	if err != nil {
+		return
+	}
+	if len(p.rings) != len(cores) {
+		p.rings = make([]*ring, len(cores))
+		for i := range p.rings {
+			p.rings[i] = newRing(sparklineWidth)
+		}
+	}
+	for i, v := range cores {
+		p.rings[i].push(v)
+	}
+	p.latest = cores
+}
+
+func (p *CPUPanel) Render(w, h int) []string {
+	var out []string
+	for i, r := range p.rings {
+		if i >= h {
+			break
+		}
+		out = append(out, fmt.Sprintf("core%-2d %s %5.1f%%", i, r.render(100), p.latest[i]))
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package panel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gsmitheidw/iotop-w/collector"
+)
+
+// DiskPanel renders one read/write throughput row per physical disk.
+type DiskPanel struct {
+	sample func() ([]collector.DiskIO, error)
+	disks  []collector.DiskIO
+}
+
+// NewDiskPanel wraps a per-disk sampler, e.g. collector.Collector.Disks.
+func NewDiskPanel(sample func() ([]collector.DiskIO, error)) *DiskPanel {
+	return &DiskPanel{sample: sample}
+}
+
+func (p *DiskPanel) Update(dt time.Duration) {
+	disks, err := p.sample()
+	if err != nil {
+		return
+	}
+	p.disks = disks
+}
+
+func (p *DiskPanel) Render(w, h int) []string {
+	var out []string
+	for i, d := range p.disks {
+		if i >= h {
+			break
+		}
+		out = append(out, fmt.Sprintf("%-10s r:%-10s w:%-10s", d.Name, formatRate(d.ReadBytesPerSec), formatRate(d.WriteBytesPerSec)))
+	}
+	return out
+}
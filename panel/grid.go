@@ -0,0 +1,107 @@
+package panel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type entry struct {
+	title   string
+	panel   Panel
+	enabled bool
+}
+
+// Grid lays out a fixed set of panels as a responsive two-column grid, falling
+// back to a single stacked column on terminals too narrow for two
+// side-by-side panels to stay readable.
+type Grid struct {
+	entries []*entry
+}
+
+// NewGrid returns an empty grid; panels are added in display order via
+// Add and toggled by their 1-based position via Toggle.
+func NewGrid() *Grid { return &Grid{} }
+
+// Add appends a titled panel, enabled by default.
+func (g *Grid) Add(title string, p Panel) {
+	g.entries = append(g.entries, &entry{title: title, panel: p, enabled: true})
+}
+
+// Toggle flips the enabled state of the nth added panel (1-indexed,
+// matching the dashboard's 1-5 keybindings).
+func (g *Grid) Toggle(n int) {
+	if n < 1 || n > len(g.entries) {
+		return
+	}
+	g.entries[n-1].enabled = !g.entries[n-1].enabled
+}
+
+// Update advances every enabled panel by dt.
+func (g *Grid) Update(dt time.Duration) {
+	for _, e := range g.entries {
+		if e.enabled {
+			e.panel.Update(dt)
+		}
+	}
+}
+
+// narrowWidth is the terminal width below which panels stack one per row
+// instead of sitting two-wide, since a narrower cell leaves sparklines
+// and labels unreadable.
+const narrowWidth = 100
+
+// Render arranges enabled panels into a 2-column grid when the terminal
+// is wide enough, otherwise stacks them one per row.
+func (g *Grid) Render(width, height int) []string {
+	var active []*entry
+	for _, e := range g.entries {
+		if e.enabled {
+			active = append(active, e)
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	cols := 1
+	if width >= narrowWidth {
+		cols = 2
+	}
+	rows := (len(active) + cols - 1) / cols
+	cellW := width / cols
+	cellH := height / rows
+	if cellH < 2 {
+		cellH = 2
+	}
+
+	var out []string
+	for row := 0; row < rows; row++ {
+		rowLines := make([]string, cellH)
+		for col := 0; col < cols; col++ {
+			idx := row*cols + col
+			var lines []string
+			if idx < len(active) {
+				lines = renderCell(active[idx], cellW, cellH)
+			}
+			for i := 0; i < cellH; i++ {
+				line := ""
+				if i < len(lines) {
+					line = lines[i]
+				}
+				rowLines[i] += fmt.Sprintf("%-*s", cellW, line)
+			}
+		}
+		out = append(out, rowLines...)
+	}
+	return out
+}
+
+func renderCell(e *entry, w, h int) []string {
+	rule := w - len(e.title) - 4
+	if rule < 0 {
+		rule = 0
+	}
+	header := fmt.Sprintf("── %s %s", e.title, strings.Repeat("─", rule))
+	return append([]string{header}, e.panel.Render(w, h-1)...)
+}
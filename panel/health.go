@@ -0,0 +1,88 @@
+package panel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gsmitheidw/iotop-w/collector"
+)
+
+// Temperature thresholds for color-coding, matching the "green under 40,
+// yellow under 55, red at/above" convention used by most vendor SMART
+// tools.
+const (
+	tempWarnC = 40
+	tempHotC  = 55
+)
+
+// ANSI color codes, duplicated from the main package's Solarized palette
+// rather than imported: main imports panel, so panel importing main back
+// would be a cycle.
+const (
+	healthGreen  = "\x1b[38;5;64m"
+	healthYellow = "\x1b[38;5;136m"
+	healthRed    = "\x1b[38;5;160m"
+	healthReset  = "\x1b[0m"
+)
+
+// DiskHealthPanel renders one row per physical drive's SMART/NVMe health:
+// temperature color-coded green/yellow/red and a warning glyph when
+// reallocated or pending sectors are nonzero. The underlying
+// collector.Collector throttles the SMART/NVMe queries themselves to
+// healthPollInterval, so calling Update every tick is cheap.
+type DiskHealthPanel struct {
+	sample func() ([]collector.DiskHealth, error)
+	disks  []collector.DiskHealth
+}
+
+// NewDiskHealthPanel wraps a health sampler, e.g. collector.Collector.Health.
+func NewDiskHealthPanel(sample func() ([]collector.DiskHealth, error)) *DiskHealthPanel {
+	return &DiskHealthPanel{sample: sample}
+}
+
+func (p *DiskHealthPanel) Update(dt time.Duration) {
+	disks, err := p.sample()
+	if err != nil {
+		return
+	}
+	p.disks = disks
+}
+
+func tempColor(c int) string {
+	switch {
+	case c >= tempHotC:
+		return healthRed
+	case c >= tempWarnC:
+		return healthYellow
+	default:
+		return healthGreen
+	}
+}
+
+func (p *DiskHealthPanel) Render(w, h int) []string {
+	var out []string
+	for i, d := range p.disks {
+		if i >= h {
+			break
+		}
+
+		if !d.Available {
+			out = append(out, fmt.Sprintf("%-20s n/a (no SMART access)", d.Device))
+			continue
+		}
+
+		warn := " "
+		if d.ReallocatedCount > 0 || d.PendingCount > 0 {
+			warn = "!"
+		}
+
+		line := fmt.Sprintf("%-20s %s%3d°C%s %s realloc:%-4d pending:%-4d",
+			d.Device, tempColor(d.TemperatureC), d.TemperatureC, healthReset,
+			warn, d.ReallocatedCount, d.PendingCount)
+		if d.NVMe {
+			line += fmt.Sprintf(" used:%d%% errors:%d", d.PercentageUsed, d.MediaErrors)
+		}
+		out = append(out, line)
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package panel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MemPanel renders a single gauge bar for used-vs-total memory.
+type MemPanel struct {
+	sample      func() (total, available uint64, err error)
+	usedFrac    float64
+	total, used uint64
+}
+
+// NewMemPanel wraps a total/available byte sampler, e.g.
+// collector.Collector.Memory.
+func NewMemPanel(sample func() (uint64, uint64, error)) *MemPanel {
+	return &MemPanel{sample: sample}
+}
+
+func (p *MemPanel) Update(dt time.Duration) {
+	total, avail, err := p.sample()
+	if err != nil || total == 0 {
+		return
+	}
+	p.total = total
+	p.used = total - avail
+	p.usedFrac = float64(p.used) / float64(total)
+}
+
+func (p *MemPanel) Render(w, h int) []string {
+	barWidth := w - 8
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	filled := int(p.usedFrac * float64(barWidth))
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+	return []string{
+		fmt.Sprintf("[%s] %4.1f%%", bar, p.usedFrac*100),
+		fmt.Sprintf("%s / %s used", formatBytes(float64(p.used)), formatBytes(float64(p.total))),
+	}
+}
@@ -0,0 +1,48 @@
+package panel
+
+import (
+	"fmt"
+	"time"
+)
+
+// NetPanel renders RX/TX sparklines sharing a common scale.
+type NetPanel struct {
+	sample  func() (rx, tx float64, err error)
+	rx, tx  *ring
+	latestR float64
+	latestT float64
+	maxSeen float64
+}
+
+// NewNetPanel wraps an aggregate rx/tx bytes-per-second sampler, e.g.
+// collector.Collector.Network.
+func NewNetPanel(sample func() (float64, float64, error)) *NetPanel {
+	return &NetPanel{
+		sample:  sample,
+		rx:      newRing(sparklineWidth),
+		tx:      newRing(sparklineWidth),
+		maxSeen: 1,
+	}
+}
+
+func (p *NetPanel) Update(dt time.Duration) {
+	rx, tx, err := p.sample()
+	if err != nil {
+		return
+	}
+	if rx > p.maxSeen || tx > p.maxSeen {
+		p.maxSeen = max(rx, tx)
+	} else {
+		p.maxSeen *= 0.95
+	}
+	p.rx.push(rx)
+	p.tx.push(tx)
+	p.latestR, p.latestT = rx, tx
+}
+
+func (p *NetPanel) Render(w, h int) []string {
+	return []string{
+		fmt.Sprintf("rx %s %s", p.rx.render(p.maxSeen), formatRate(p.latestR)),
+		fmt.Sprintf("tx %s %s", p.tx.render(p.maxSeen), formatRate(p.latestT)),
+	}
+}
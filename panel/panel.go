@@ -0,0 +1,19 @@
+// Package panel implements the tiles shown in --dashboard mode: small
+// self-contained widgets that accumulate samples over time and draw
+// themselves into a fixed character grid.
+package panel
+
+import "time"
+
+// sparklineWidth is the number of samples kept for history-based panels,
+// matching the process table's own history ring width.
+const sparklineWidth = 30
+
+// Panel is a single dashboard tile. Update accumulates a new sample;
+// Render draws the panel's current state into w columns by h rows,
+// returning one string per row (shorter than h if the panel has nothing
+// to show on a given row).
+type Panel interface {
+	Update(dt time.Duration)
+	Render(w, h int) []string
+}
@@ -0,0 +1,61 @@
+package panel
+
+import (
+	"fmt"
+	"strings"
+)
+
+var blockChars = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// ring is a fixed-size history buffer rendered as a block sparkline.
+// Panels keep one of these per series (one per CPU core, rx/tx, etc.).
+type ring struct {
+	buf  []float64
+	head int
+}
+
+func newRing(n int) *ring { return &ring{buf: make([]float64, n)} }
+
+func (r *ring) push(v float64) {
+	r.buf[r.head] = v
+	r.head = (r.head + 1) % len(r.buf)
+}
+
+func (r *ring) render(max float64) string {
+	var b strings.Builder
+	for i := 0; i < len(r.buf); i++ {
+		v := r.buf[(r.head+i)%len(r.buf)]
+		idx := 0
+		if max > 0 {
+			idx = int(v/max*float64(len(blockChars)-1) + 0.5)
+		}
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(blockChars) {
+			idx = len(blockChars) - 1
+		}
+		b.WriteRune(blockChars[idx])
+	}
+	return b.String()
+}
+
+// formatBytes formats a byte count into human-readable form, the same
+// scale as the process table's formatBytes.
+func formatBytes(bytes float64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%.0f B", bytes)
+	}
+	div, exp := unit, 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", bytes/float64(div), "KMGTPE"[exp])
+}
+
+// formatRate formats bytes/sec into human-readable form.
+func formatRate(bytesPerSec float64) string {
+	return formatBytes(bytesPerSec) + "/s"
+}
@@ -0,0 +1,58 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// keys buffers single bytes read from stdin by the reader goroutine so
+// readKey can poll it without blocking the main select loop.
+var keys = make(chan rune, 16)
+
+// platformInit puts stdin into raw mode (no line buffering, no echo) and
+// starts a background reader feeding keys, returning a restore function.
+func platformInit() func() {
+	fd := int(os.Stdin.Fd())
+	orig, err := term.MakeRaw(fd)
+	if err != nil {
+		return func() {}
+	}
+
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			if n > 0 {
+				keys <- rune(buf[0])
+			}
+		}
+	}()
+
+	return func() { term.Restore(fd, orig) }
+}
+
+// readKey returns the next buffered key press, if any, without blocking.
+func readKey() (rune, bool) {
+	select {
+	case ch := <-keys:
+		return ch, true
+	default:
+		return 0, false
+	}
+}
+
+// termSize returns the terminal's visible width and height in characters,
+// used to decide the --dashboard grid layout.
+func termSize() (int, int) {
+	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 80, 24
+	}
+	return w, h
+}
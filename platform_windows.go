@@ -0,0 +1,113 @@
+//go:build windows
+
+package main
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	k32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	pGetNumberOfConsoleInputEvents = k32.NewProc("GetNumberOfConsoleInputEvents")
+	pReadConsoleInputW             = k32.NewProc("ReadConsoleInputW")
+	pFlushConsoleInputBuffer       = k32.NewProc("FlushConsoleInputBuffer")
+)
+
+const (
+	keyEvent = 0x0001
+)
+
+type inputRecord struct {
+	EventType uint16
+	_         [2]byte
+	Event     [16]byte
+}
+
+type keyEventRecord struct {
+	KeyDown         int32
+	RepeatCount     uint16
+	VirtualKeyCode  uint16
+	VirtualScanCode uint16
+	Char            uint16
+	ControlKeyState uint32
+}
+
+// platformInit enables VT100 escape processing on stdout and turns off
+// line input & echo on stdin, returning a restore function.
+func platformInit() func() {
+	hOut, _ := windows.GetStdHandle(windows.STD_OUTPUT_HANDLE)
+	var outMode uint32
+	windows.GetConsoleMode(hOut, &outMode)
+	windows.SetConsoleMode(hOut, outMode|0x0004)
+
+	hIn, _ := windows.GetStdHandle(windows.STD_INPUT_HANDLE)
+	var inMode uint32
+	windows.GetConsoleMode(hIn, &inMode)
+	orig := inMode
+	inMode &^= windows.ENABLE_ECHO_INPUT | windows.ENABLE_LINE_INPUT
+	windows.SetConsoleMode(hIn, inMode)
+
+	return func() {
+		windows.SetConsoleMode(hIn, orig)
+		pFlushConsoleInputBuffer.Call(uintptr(hIn))
+	}
+}
+
+// readKey checks for key presses via console input events, returning
+// immediately if none are pending.
+func readKey() (rune, bool) {
+	h, err := windows.GetStdHandle(windows.STD_INPUT_HANDLE)
+	if err != nil {
+		return 0, false
+	}
+
+	var numEvents uint32
+	r, _, _ := pGetNumberOfConsoleInputEvents.Call(uintptr(h), uintptr(unsafe.Pointer(&numEvents)))
+	if r == 0 || numEvents == 0 {
+		return 0, false
+	}
+
+	records := make([]inputRecord, 1)
+	var read uint32
+	r, _, _ = pReadConsoleInputW.Call(
+		uintptr(h),
+		uintptr(unsafe.Pointer(&records[0])),
+		1,
+		uintptr(unsafe.Pointer(&read)),
+	)
+	if r == 0 || read == 0 {
+		return 0, false
+	}
+
+	rec := records[0]
+	if rec.EventType != keyEvent {
+		return 0, false
+	}
+
+	// Parse key event
+	ke := (*keyEventRecord)(unsafe.Pointer(&rec.Event[0]))
+	if ke.KeyDown == 0 {
+		return 0, false // only care about key down
+	}
+
+	return rune(ke.Char), true
+}
+
+// termSize returns the console's visible width and height in characters,
+// used to decide the --dashboard grid layout.
+func termSize() (int, int) {
+	h, err := windows.GetStdHandle(windows.STD_OUTPUT_HANDLE)
+	if err != nil {
+		return 80, 24
+	}
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(h, &info); err != nil {
+		return 80, 24
+	}
+	w := int(info.Window.Right - info.Window.Left + 1)
+	height := int(info.Window.Bottom - info.Window.Top + 1)
+	return w, height
+}
@@ -0,0 +1,112 @@
+// Package sampler turns raw collector.Collector polls into per-tick
+// Samples (process rates plus disk pressure) and defines the Exporter
+// interface. The Sampler does not deliver Samples itself: its caller
+// passes each one to the renderer and to any configured Exporters, so
+// the TUI and --export modes share a single source of truth instead of
+// polling the collector twice.
+package sampler
+
+import (
+	"time"
+
+	"github.com/gsmitheidw/iotop-w/collector"
+)
+
+// Rate is one process's IO throughput since the previous sample.
+type Rate struct {
+	PID   uint32
+	Name  string
+	User  string
+	Read  float64 // bytes/sec
+	Write float64 // bytes/sec
+	Total float64 // bytes/sec
+}
+
+// Sample is everything gathered in a single tick.
+type Sample struct {
+	Timestamp time.Time
+	Rates     []Rate
+	DiskQueue float64
+}
+
+// Exporter publishes a Sample somewhere outside the TUI, e.g. a
+// Prometheus /metrics endpoint or an NDJSON stream.
+type Exporter interface {
+	Export(Sample) error
+	Close() error
+}
+
+// Sampler polls a collector.Collector once per Sample call, diffing
+// cumulative process IO counters against the previous call to produce
+// rates.
+type Sampler struct {
+	c        collector.Collector
+	prevSnap map[uint32]collector.ProcIO
+	prevTime time.Time
+}
+
+// New wraps a collector.Collector; the first Sample call has no prior
+// snapshot to diff against and returns no rates.
+func New(c collector.Collector) *Sampler {
+	return &Sampler{c: c}
+}
+
+// Reset discards the previous snapshot, so the next Sample call reports
+// no rates instead of computing them against counters from before a
+// pause: the elapsed wall-clock time would include the paused duration
+// and spike every rate.
+func (s *Sampler) Reset() {
+	s.prevSnap = nil
+	s.prevTime = time.Time{}
+}
+
+// Sample polls the collector and returns the rates and disk pressure
+// since the previous call.
+func (s *Sampler) Sample() (Sample, error) {
+	procs, err := s.c.Processes()
+	if err != nil {
+		return Sample{}, err
+	}
+	now := time.Now()
+	curr := make(map[uint32]collector.ProcIO, len(procs))
+	for _, p := range procs {
+		curr[p.PID] = p
+	}
+
+	elapsed := now.Sub(s.prevTime).Seconds()
+	if elapsed <= 0 {
+		elapsed = 0.001 // prevent division by zero on the first tick
+	}
+
+	var rates []Rate
+	if s.prevSnap != nil {
+		for pid, p := range curr {
+			old, ok := s.prevSnap[pid]
+			if !ok {
+				continue
+			}
+			rDelta := float64(p.Read - old.Read)
+			wDelta := float64(p.Write - old.Write)
+			if rDelta+wDelta == 0 {
+				continue
+			}
+			rRate := rDelta / elapsed
+			wRate := wDelta / elapsed
+			rates = append(rates, Rate{
+				PID:   pid,
+				Name:  p.Name,
+				User:  p.User,
+				Read:  rRate,
+				Write: wRate,
+				Total: rRate + wRate,
+			})
+		}
+	}
+
+	q, _ := s.c.DiskPressure()
+
+	s.prevSnap = curr
+	s.prevTime = now
+
+	return Sample{Timestamp: now, Rates: rates, DiskQueue: q}, nil
+}